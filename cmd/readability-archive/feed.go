@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title   string `xml:"title"`
+	ID      string `xml:"id"`
+	Updated string `xml:"updated"`
+	Link    atomLink
+	Summary string `xml:"summary"`
+}
+
+type atomLink struct {
+	XMLName xml.Name `xml:"link"`
+	Href    string   `xml:"href,attr"`
+}
+
+// writeAtomFeed writes atom.xml listing every archived item. baseURL, if
+// set, is prefixed to each entry's archive path so the feed can be served
+// from anywhere; otherwise entries link to the original article URL.
+func writeAtomFeed(outDir string, items []*item, baseURL string) error {
+	feed := atomFeed{
+		Title:   "Archive",
+		ID:      "urn:go-readability:archive",
+		Updated: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	for _, it := range items {
+		link := it.URL
+		if baseURL != "" {
+			link = strings.TrimRight(baseURL, "/") + "/articles/" + it.Slug + ".html"
+		}
+
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:   it.Article.Title,
+			ID:      "urn:go-readability:archive:" + it.Slug,
+			Updated: it.SavedAt.Format(time.RFC3339),
+			Link:    atomLink{Href: link},
+			Summary: it.Article.Excerpt,
+		})
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal atom feed: %v", err)
+	}
+
+	out := append([]byte(xml.Header), data...)
+	if err = os.WriteFile(filepath.Join(outDir, "atom.xml"), out, 0o644); err != nil {
+		return fmt.Errorf("failed to write atom.xml: %v", err)
+	}
+
+	return nil
+}
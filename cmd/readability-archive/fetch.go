@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	nurl "net/url"
+	"sync"
+	"time"
+
+	"github.com/vissong/go-readability"
+)
+
+// item is one archived article, successful or not.
+type item struct {
+	URL     string
+	Slug    string
+	SavedAt time.Time
+	Article readability.Article
+	Err     error
+}
+
+// fetchAll downloads and extracts every URL using a bounded pool of
+// workers, returning results in the same order as urls.
+func fetchAll(urls []string, workers int) []*item {
+	if workers < 1 {
+		workers = 1
+	}
+
+	items := make([]*item, len(urls))
+	for i, u := range urls {
+		items[i] = &item{URL: u, Slug: fmt.Sprintf("article-%03d", i+1)}
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				archiveOne(items[i])
+			}
+		}()
+	}
+
+	for i := range items {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return items
+}
+
+func archiveOne(it *item) {
+	if _, err := nurl.ParseRequestURI(it.URL); err != nil {
+		it.Err = fmt.Errorf("invalid url: %v", err)
+		return
+	}
+
+	article, err := readability.FromURL(it.URL, 30*time.Second)
+	if err != nil {
+		it.Err = fmt.Errorf("failed to extract: %v", err)
+		return
+	}
+
+	it.Article = article
+	it.SavedAt = time.Now().UTC()
+}
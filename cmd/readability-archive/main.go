@@ -0,0 +1,53 @@
+// Command readability-archive turns a list of URLs (plain text, one per
+// line, or an OPML/RSS feed) into a static, browsable read-later archive:
+// one HTML file per article plus an index, a search index, and an Atom
+// feed of everything that was saved.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+)
+
+func main() {
+	var (
+		sourcePath = flag.String("sources", "", "path to a newline-delimited URL list, or an OPML/RSS file")
+		outDir     = flag.String("out", "archive", "output directory for the generated site")
+		workers    = flag.Int("workers", 8, "number of concurrent fetch workers")
+		sortBy     = flag.String("sort", "date", `how to sort the index: "date" or "site"`)
+		baseURL    = flag.String("base-url", "", "base URL the archive will be served from, used in atom.xml links")
+	)
+	flag.Parse()
+
+	if *sourcePath == "" {
+		log.Fatalln("-sources is required")
+	}
+
+	urls, err := loadSources(*sourcePath)
+	if err != nil {
+		log.Fatalf("failed to load sources: %v\n", err)
+	}
+	if len(urls) == 0 {
+		log.Fatalln("no URLs found in sources")
+	}
+
+	items := fetchAll(urls, *workers)
+
+	if err = os.MkdirAll(*outDir, 0o755); err != nil {
+		log.Fatalf("failed to create output dir: %v\n", err)
+	}
+
+	if err = writeArchive(*outDir, items, *sortBy, *baseURL); err != nil {
+		log.Fatalf("failed to write archive: %v\n", err)
+	}
+
+	var failed int
+	for _, item := range items {
+		if item.Err != nil {
+			failed++
+			log.Printf("failed to archive %s: %v\n", item.URL, item.Err)
+		}
+	}
+	log.Printf("archived %d/%d articles to %s\n", len(items)-failed, len(items), *outDir)
+}
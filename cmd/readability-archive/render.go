@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+var articleTemplate = template.Must(template.New("article").Parse(`<!DOCTYPE html>
+<html lang="{{.Language}}">
+<head>
+  <meta charset="utf-8">
+  <title>{{.Title}}</title>
+  <link rel="stylesheet" href="../style.css">
+</head>
+<body>
+  <nav><a href="../index.html">&larr; Archive</a></nav>
+  <article>
+    <h1>{{.Title}}</h1>
+    <p class="meta">{{.Byline}} &middot; {{.SiteName}} &middot; saved {{.SavedAt.Format "2006-01-02"}}</p>
+    <div class="content">{{.Content}}</div>
+  </article>
+</body>
+</html>
+`))
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Archive</title>
+  <link rel="stylesheet" href="style.css">
+</head>
+<body>
+  <h1>Archive</h1>
+  <ul class="articles">
+  {{range .}}
+    <li>
+      <a href="articles/{{.Slug}}.html">{{.Article.Title}}</a>
+      <p class="meta">{{.Article.Byline}} &middot; {{.Article.SiteName}} &middot; {{.SavedAt.Format "2006-01-02"}}</p>
+      <p class="excerpt">{{.Article.Excerpt}}</p>
+    </li>
+  {{end}}
+  </ul>
+</body>
+</html>
+`))
+
+const sharedCSS = `body { font-family: Georgia, serif; max-width: 40rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+nav { margin-bottom: 1.5rem; }
+.meta { color: #666; font-size: 0.9rem; }
+.excerpt { color: #444; }
+.articles { list-style: none; padding: 0; }
+.articles li { margin-bottom: 1.5rem; }
+article img { max-width: 100%; }
+`
+
+// writeArchive renders the per-article pages, the index, the search
+// index, and the atom feed into outDir. Items whose extraction failed are
+// skipped but counted by the caller.
+func writeArchive(outDir string, items []*item, sortBy, baseURL string) error {
+	ok := make([]*item, 0, len(items))
+	for _, it := range items {
+		if it.Err == nil {
+			ok = append(ok, it)
+		}
+	}
+	sortItems(ok, sortBy)
+
+	articlesDir := filepath.Join(outDir, "articles")
+	if err := os.MkdirAll(articlesDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create articles dir: %v", err)
+	}
+
+	for _, it := range ok {
+		var buf bytes.Buffer
+		if err := articleTemplate.Execute(&buf, struct {
+			Title, Language, Byline, SiteName string
+			Content                           template.HTML
+			SavedAt                           time.Time
+		}{
+			Title:    it.Article.Title,
+			Language: it.Article.Language,
+			Byline:   it.Article.Byline,
+			SiteName: it.Article.SiteName,
+			Content:  template.HTML(renderContent(it.Article.Node)),
+			SavedAt:  it.SavedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to render %s: %v", it.URL, err)
+		}
+
+		if err := os.WriteFile(filepath.Join(articlesDir, it.Slug+".html"), buf.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", it.Slug, err)
+		}
+	}
+
+	var indexBuf bytes.Buffer
+	if err := indexTemplate.Execute(&indexBuf, ok); err != nil {
+		return fmt.Errorf("failed to render index: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(outDir, "index.html"), indexBuf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write index.html: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(outDir, "style.css"), []byte(sharedCSS), 0o644); err != nil {
+		return fmt.Errorf("failed to write style.css: %v", err)
+	}
+
+	if err := writeSearchIndex(outDir, ok); err != nil {
+		return err
+	}
+
+	return writeAtomFeed(outDir, ok, baseURL)
+}
+
+func sortItems(items []*item, sortBy string) {
+	switch sortBy {
+	case "site":
+		sort.Slice(items, func(i, j int) bool {
+			return items[i].Article.SiteName < items[j].Article.SiteName
+		})
+	default:
+		sort.Slice(items, func(i, j int) bool {
+			return items[i].SavedAt.After(items[j].SavedAt)
+		})
+	}
+}
+
+func renderContent(node *html.Node) string {
+	if node == nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := html.Render(&buf, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+type searchEntry struct {
+	Title   string `json:"title"`
+	Excerpt string `json:"excerpt"`
+	URL     string `json:"url"`
+	Path    string `json:"path"`
+}
+
+func writeSearchIndex(outDir string, items []*item) error {
+	entries := make([]searchEntry, 0, len(items))
+	for _, it := range items {
+		entries = append(entries, searchEntry{
+			Title:   it.Article.Title,
+			Excerpt: it.Article.Excerpt,
+			URL:     it.URL,
+			Path:    "articles/" + it.Slug + ".html",
+		})
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal search index: %v", err)
+	}
+
+	if err = os.WriteFile(filepath.Join(outDir, "search.json"), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write search.json: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// opml is just enough of the OPML format to pull out feed/article URLs
+// from <outline xmlUrl="..."> or <outline htmlUrl="..."> elements.
+type opml struct {
+	XMLName xml.Name `xml:"opml"`
+	Body    opmlBody `xml:"body"`
+}
+
+type opmlBody struct {
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+type opmlOutline struct {
+	XMLURL   string        `xml:"xmlUrl,attr"`
+	HTMLURL  string        `xml:"htmlUrl,attr"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// rss is just enough of an RSS 2.0 feed to pull out item links.
+type rss struct {
+	XMLName xml.Name `xml:"rss"`
+	Channel struct {
+		Items []struct {
+			Link string `xml:"link"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+// atomFeedIn is just enough of an Atom feed to pull out entry links.
+type atomFeedIn struct {
+	XMLName xml.Name `xml:"feed"`
+	Entries []struct {
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+	} `xml:"entry"`
+}
+
+var feedHTTPClient = &http.Client{Timeout: 15 * time.Second}
+
+// loadSources reads sourcePath and returns the list of article URLs it
+// contains. Plain text files are read one URL per line; a file that
+// parses as OPML has each of its subscriptions' feeds fetched and
+// expanded into that feed's article links (falling back to the
+// subscription's htmlUrl if the feed can't be fetched or parsed); a file
+// that parses as a bare RSS/Atom feed contributes its own item links.
+func loadSources(sourcePath string) ([]string, error) {
+	data, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", sourcePath, err)
+	}
+
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "<?xml") || strings.HasPrefix(trimmed, "<opml") || strings.HasPrefix(trimmed, "<rss") || strings.HasPrefix(trimmed, "<feed") {
+		if outlines, ok := parseOPML(data); ok {
+			return expandOutlines(outlines), nil
+		}
+		if urls, ok := parseFeedLinks(data); ok {
+			return urls, nil
+		}
+		return nil, fmt.Errorf("%s looks like XML but is not valid OPML, RSS, or Atom", sourcePath)
+	}
+
+	return parseURLList(data)
+}
+
+func parseURLList(data []byte) ([]string, error) {
+	var urls []string
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	return urls, scanner.Err()
+}
+
+// parseOPML returns every outline in doc that names a feed or page, in
+// document order, flattening nested <outline> groups.
+func parseOPML(data []byte) ([]opmlOutline, bool) {
+	var doc opml
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, false
+	}
+
+	var outlines []opmlOutline
+	var walk func([]opmlOutline)
+	walk = func(children []opmlOutline) {
+		for _, o := range children {
+			if o.XMLURL != "" || o.HTMLURL != "" {
+				outlines = append(outlines, o)
+			}
+			walk(o.Outlines)
+		}
+	}
+	walk(doc.Body.Outlines)
+
+	return outlines, len(outlines) > 0
+}
+
+// expandOutlines resolves each OPML subscription to the article URLs it
+// contributes: the feed at xmlUrl is fetched and its item links used; if
+// that fails or the feed has no items, htmlUrl (the subscription's own
+// page) is used as a single-article fallback.
+func expandOutlines(outlines []opmlOutline) []string {
+	var urls []string
+	for _, o := range outlines {
+		if o.XMLURL != "" {
+			if feedURLs, ok := fetchFeedLinks(o.XMLURL); ok {
+				urls = append(urls, feedURLs...)
+				continue
+			}
+		}
+		if o.HTMLURL != "" {
+			urls = append(urls, o.HTMLURL)
+		}
+	}
+	return urls
+}
+
+func fetchFeedLinks(feedURL string) ([]string, bool) {
+	resp, err := feedHTTPClient.Get(feedURL)
+	if err != nil {
+		return nil, false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, false
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false
+	}
+
+	return parseFeedLinks(data)
+}
+
+// parseFeedLinks extracts article links from a raw RSS or Atom feed.
+func parseFeedLinks(data []byte) ([]string, bool) {
+	if urls, ok := parseRSS(data); ok {
+		return urls, true
+	}
+	return parseAtom(data)
+}
+
+func parseRSS(data []byte) ([]string, bool) {
+	var doc rss
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, false
+	}
+
+	var urls []string
+	for _, item := range doc.Channel.Items {
+		if item.Link != "" {
+			urls = append(urls, item.Link)
+		}
+	}
+	return urls, len(urls) > 0
+}
+
+func parseAtom(data []byte) ([]string, bool) {
+	var doc atomFeedIn
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, false
+	}
+
+	var urls []string
+	for _, entry := range doc.Entries {
+		for _, link := range entry.Links {
+			if link.Rel == "" || link.Rel == "alternate" {
+				if link.Href != "" {
+					urls = append(urls, link.Href)
+				}
+				break
+			}
+		}
+	}
+	return urls, len(urls) > 0
+}
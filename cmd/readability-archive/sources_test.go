@@ -0,0 +1,71 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExpandOutlinesFetchesFeedItems(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = w.Write([]byte(`<rss><channel>
+			<item><link>https://example.com/a</link></item>
+			<item><link>https://example.com/b</link></item>
+		</channel></rss>`))
+	}))
+	defer srv.Close()
+
+	outlines := []opmlOutline{{XMLURL: srv.URL, HTMLURL: "https://example.com/blog"}}
+
+	urls := expandOutlines(outlines)
+
+	if len(urls) != 2 || urls[0] != "https://example.com/a" || urls[1] != "https://example.com/b" {
+		t.Fatalf("expected the feed's own item links, got %v", urls)
+	}
+}
+
+func TestExpandOutlinesFallsBackToHTMLURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	outlines := []opmlOutline{{XMLURL: srv.URL, HTMLURL: "https://example.com/blog"}}
+
+	urls := expandOutlines(outlines)
+
+	if len(urls) != 1 || urls[0] != "https://example.com/blog" {
+		t.Fatalf("expected fallback to htmlUrl when the feed can't be fetched, got %v", urls)
+	}
+}
+
+func TestParseOPMLFlattensNestedOutlines(t *testing.T) {
+	doc := `<opml><body>
+		<outline text="Tech">
+			<outline text="Blog A" xmlUrl="https://a.example.com/feed" htmlUrl="https://a.example.com"/>
+		</outline>
+	</body></opml>`
+
+	outlines, ok := parseOPML([]byte(doc))
+	if !ok || len(outlines) != 1 {
+		t.Fatalf("expected one nested outline to be found, got %v (ok=%v)", outlines, ok)
+	}
+	if outlines[0].XMLURL != "https://a.example.com/feed" {
+		t.Fatalf("unexpected xmlUrl: %q", outlines[0].XMLURL)
+	}
+}
+
+func TestParseAtomLinks(t *testing.T) {
+	doc := `<feed><entry><link rel="alternate" href="https://example.com/post-1"/></entry></feed>`
+
+	urls, ok := parseAtom([]byte(doc))
+	if !ok || len(urls) != 1 || urls[0] != "https://example.com/post-1" {
+		t.Fatalf("expected one atom entry link, got %v (ok=%v)", urls, ok)
+	}
+
+	if _, ok := parseAtom([]byte(strings.TrimSpace(doc))[:5]); ok {
+		t.Fatalf("expected malformed xml to fail parsing")
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/vissong/go-readability"
+)
+
+// cacheEntry is one cached extraction, keyed by the source URL. ETag and
+// LastModified (if the origin sent them) let us revalidate cheaply with
+// If-None-Match / If-Modified-Since instead of re-extracting from scratch.
+type cacheEntry struct {
+	URL          string
+	ETag         string
+	LastModified string
+	Article      readability.Article
+	Readerable   bool
+	CachedAt     time.Time
+}
+
+// articleCache is a fixed-size, in-memory LRU cache of extracted articles
+// keyed by source URL.
+type articleCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+func newArticleCache(capacity int) *articleCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &articleCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *articleCache) Get(url string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[url]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	c.ll.MoveToFront(el)
+	return el.Value.(cacheEntry), true
+}
+
+func (c *articleCache) Set(entry cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[entry.URL]; ok {
+		el.Value = entry
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	el := c.ll.PushFront(entry)
+	c.items[entry.URL] = el
+
+	if c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(cacheEntry).URL)
+		}
+	}
+}
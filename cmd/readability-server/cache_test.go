@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+func TestArticleCacheGetSet(t *testing.T) {
+	c := newArticleCache(2)
+
+	if _, ok := c.Get("https://example.com/a"); ok {
+		t.Fatalf("expected empty cache miss")
+	}
+
+	c.Set(cacheEntry{URL: "https://example.com/a", ETag: "a"})
+	entry, ok := c.Get("https://example.com/a")
+	if !ok || entry.ETag != "a" {
+		t.Fatalf("expected cached entry for a, got %+v (ok=%v)", entry, ok)
+	}
+}
+
+func TestArticleCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newArticleCache(2)
+
+	c.Set(cacheEntry{URL: "https://example.com/a"})
+	c.Set(cacheEntry{URL: "https://example.com/b"})
+
+	// Touch a so it becomes more recently used than b.
+	if _, ok := c.Get("https://example.com/a"); !ok {
+		t.Fatalf("expected a to be cached")
+	}
+
+	c.Set(cacheEntry{URL: "https://example.com/c"})
+
+	if _, ok := c.Get("https://example.com/b"); ok {
+		t.Fatalf("expected b to have been evicted as least recently used")
+	}
+	if _, ok := c.Get("https://example.com/a"); !ok {
+		t.Fatalf("expected a to still be cached")
+	}
+	if _, ok := c.Get("https://example.com/c"); !ok {
+		t.Fatalf("expected c to be cached")
+	}
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	nurl "net/url"
+	"strings"
+	"time"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+
+	"github.com/vissong/go-readability"
+	"github.com/vissong/go-readability/export"
+)
+
+type serverConfig struct {
+	cache      *articleCache
+	limiter    *hostLimiter
+	httpClient *http.Client
+}
+
+type server struct {
+	cfg serverConfig
+}
+
+func newServer(cfg serverConfig) *server {
+	return &server{cfg: cfg}
+}
+
+// extractRequest is the body accepted by POST /extract.
+type extractRequest struct {
+	URL  string `json:"url"`
+	HTML string `json:"html"`
+}
+
+// extractResponse mirrors readability.Article in the shape documented for
+// the HTTP API.
+type extractResponse struct {
+	Title       string `json:"title"`
+	Byline      string `json:"byline"`
+	Excerpt     string `json:"excerpt"`
+	SiteName    string `json:"siteName"`
+	Image       string `json:"image"`
+	Favicon     string `json:"favicon"`
+	Length      int    `json:"length"`
+	Content     string `json:"content"`
+	TextContent string `json:"textContent"`
+	Language    string `json:"language"`
+	Readerable  bool   `json:"readerable"`
+}
+
+func (s *server) handleExtract(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	pageURL, rawHTML, err := readExtractInput(r)
+	if err != nil {
+		requestsTotal.WithLabelValues("bad_request").Inc()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	parsedURL, err := nurl.ParseRequestURI(pageURL)
+	if err != nil {
+		requestsTotal.WithLabelValues("bad_request").Inc()
+		http.Error(w, fmt.Sprintf("invalid url: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if !s.cfg.limiter.Allow(parsedURL.Host) {
+		requestsTotal.WithLabelValues("rate_limited").Inc()
+		http.Error(w, "rate limit exceeded for this host", http.StatusTooManyRequests)
+		return
+	}
+
+	article, readerable, err := s.extract(r, parsedURL, rawHTML)
+	if err != nil {
+		requestsTotal.WithLabelValues("extraction_error").Inc()
+		hostErrorsTotal.WithLabelValues(boundedHostLabel(parsedURL.Host)).Inc()
+		http.Error(w, fmt.Sprintf("failed to extract article: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	requestsTotal.WithLabelValues("ok").Inc()
+	s.writeResponse(w, r, parsedURL, article, readerable)
+}
+
+// readExtractInput accepts either a JSON body {"url": "..."}, a raw HTML
+// body with ?url= for base-URL resolution, or ?url= alone for a GET.
+func readExtractInput(r *http.Request) (pageURL, rawHTML string, err error) {
+	queryURL := r.URL.Query().Get("url")
+
+	if r.Method == http.MethodGet {
+		if queryURL == "" {
+			return "", "", fmt.Errorf("url query parameter is required")
+		}
+		return queryURL, "", nil
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read request body: %v", err)
+	}
+
+	if strings.HasPrefix(contentType, "application/json") {
+		var req extractRequest
+		if err = json.Unmarshal(body, &req); err != nil {
+			return "", "", fmt.Errorf("invalid json body: %v", err)
+		}
+		if req.URL == "" && req.HTML == "" {
+			return "", "", fmt.Errorf("one of url or html is required")
+		}
+		if req.HTML != "" {
+			if queryURL == "" && req.URL == "" {
+				return "", "", fmt.Errorf("url query parameter is required to resolve relative links in html")
+			}
+			if req.URL == "" {
+				req.URL = queryURL
+			}
+			return req.URL, req.HTML, nil
+		}
+		return req.URL, "", nil
+	}
+
+	// Raw HTML body; the base URL must come from the query string.
+	if queryURL == "" {
+		return "", "", fmt.Errorf("url query parameter is required to resolve relative links in html")
+	}
+	return queryURL, string(body), nil
+}
+
+func (s *server) extract(r *http.Request, parsedURL *nurl.URL, rawHTML string) (readability.Article, bool, error) {
+	if rawHTML != "" {
+		doc, err := dom.Parse(strings.NewReader(rawHTML))
+		if err != nil {
+			return readability.Article{}, false, fmt.Errorf("failed to parse html: %v", err)
+		}
+		article, err := readability.FromDocument(doc, parsedURL)
+		if err != nil {
+			return readability.Article{}, false, err
+		}
+		return article, readability.CheckDocument(doc), nil
+	}
+
+	if err := validateOutboundURL(parsedURL); err != nil {
+		return readability.Article{}, false, fmt.Errorf("refusing to fetch url: %v", err)
+	}
+
+	if entry, ok := s.cfg.cache.Get(parsedURL.String()); ok {
+		if fresh, err := s.revalidate(r.Context(), parsedURL, entry); err == nil && fresh {
+			cacheHitsTotal.WithLabelValues("hit").Inc()
+			return entry.Article, entry.Readerable, nil
+		}
+	}
+	cacheHitsTotal.WithLabelValues("miss").Inc()
+
+	start := time.Now()
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, parsedURL.String(), nil)
+	if err != nil {
+		return readability.Article{}, false, err
+	}
+
+	resp, err := s.cfg.httpClient.Do(req)
+	if err != nil {
+		return readability.Article{}, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return readability.Article{}, false, fmt.Errorf("origin returned status %d", resp.StatusCode)
+	}
+
+	doc, err := dom.Parse(resp.Body)
+	if err != nil {
+		return readability.Article{}, false, fmt.Errorf("failed to parse origin response: %v", err)
+	}
+
+	article, err := readability.FromDocument(doc, parsedURL)
+	if err != nil {
+		return readability.Article{}, false, err
+	}
+	extractionDuration.Observe(time.Since(start).Seconds())
+
+	readerable := readability.CheckDocument(doc)
+	s.cfg.cache.Set(cacheEntry{
+		URL:          parsedURL.String(),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Article:      article,
+		Readerable:   readerable,
+		CachedAt:     time.Now(),
+	})
+
+	return article, readerable, nil
+}
+
+// revalidate asks the origin whether entry is still current using
+// If-None-Match / If-Modified-Since, reporting fresh=true on a 304.
+func (s *server) revalidate(ctx context.Context, parsedURL *nurl.URL, entry cacheEntry) (fresh bool, err error) {
+	if entry.ETag == "" && entry.LastModified == "" {
+		return false, fmt.Errorf("no validator cached")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsedURL.String(), nil)
+	if err != nil {
+		return false, err
+	}
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+
+	resp, err := s.cfg.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusNotModified, nil
+}
+
+func (s *server) writeResponse(w http.ResponseWriter, r *http.Request, parsedURL *nurl.URL, article readability.Article, readerable bool) {
+	switch negotiateContentType(r) {
+	case "application/epub+zip":
+		w.Header().Set("Content-Type", "application/epub+zip")
+		w.Header().Set("Content-Disposition", `attachment; filename="article.epub"`)
+		// article may be a cached entry shared with concurrent requests for
+		// the same URL; export on a cloned node so WriteEPUB can't affect
+		// what those requests (or a later cache read) see, regardless of
+		// whether export itself clones internally.
+		epubArticle := article
+		if article.Node != nil {
+			epubArticle.Node = dom.Clone(article.Node, true)
+		}
+		if err := export.WriteEPUB(w, epubArticle, export.Options{BaseURL: parsedURL}); err != nil {
+			http.Error(w, fmt.Sprintf("failed to render epub: %v", err), http.StatusInternalServerError)
+		}
+	case "text/html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		var buf bytes.Buffer
+		if article.Node != nil {
+			_ = html.Render(&buf, article.Node)
+		}
+		_, _ = buf.WriteTo(w)
+	default:
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_ = json.NewEncoder(w).Encode(extractResponse{
+			Title:       article.Title,
+			Byline:      article.Byline,
+			Excerpt:     article.Excerpt,
+			SiteName:    article.SiteName,
+			Image:       article.Image,
+			Favicon:     article.Favicon,
+			Length:      article.Length,
+			Content:     article.Content,
+			TextContent: article.TextContent,
+			Language:    article.Language,
+			Readerable:  readerable,
+		})
+	}
+}
+
+// negotiateContentType inspects ?format= and the Accept header to decide
+// which representation to render.
+func negotiateContentType(r *http.Request) string {
+	switch r.URL.Query().Get("format") {
+	case "epub":
+		return "application/epub+zip"
+	case "html":
+		return "text/html"
+	case "json":
+		return "application/json"
+	}
+
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, "application/epub+zip"):
+		return "application/epub+zip"
+	case strings.Contains(accept, "text/html"):
+		return "text/html"
+	default:
+		return "application/json"
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	nurl "net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+
+	readability "github.com/vissong/go-readability"
+)
+
+func TestNegotiateContentType(t *testing.T) {
+	cases := []struct {
+		name   string
+		format string
+		accept string
+		want   string
+	}{
+		{name: "format query wins over accept", format: "epub", accept: "application/json", want: "application/epub+zip"},
+		{name: "format=html", format: "html", want: "text/html"},
+		{name: "format=json", format: "json", want: "application/json"},
+		{name: "accept epub", accept: "application/epub+zip", want: "application/epub+zip"},
+		{name: "accept html", accept: "text/html,application/xhtml+xml", want: "text/html"},
+		{name: "default is json", want: "application/json"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/extract?format="+tc.format, nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+			if got := negotiateContentType(r); got != tc.want {
+				t.Fatalf("negotiateContentType() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWriteResponseEPUBDoesNotMutateCachedNode(t *testing.T) {
+	img := imgServer(t)
+	defer img.Close()
+
+	doc, err := dom.Parse(strings.NewReader(`<html><body><article>
+		<p>intro</p>
+		<img src="` + img.URL + `/cover.png">
+	</article></body></html>`))
+	if err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+
+	article := readability.Article{Node: dom.QuerySelector(doc, "article")}
+	cache := newArticleCache(10)
+	cache.Set(cacheEntry{URL: "https://example.com/a", Article: article})
+
+	parsedURL, _ := nurl.ParseRequestURI("https://example.com/a")
+	s := &server{cfg: serverConfig{cache: cache}}
+
+	r := httptest.NewRequest(http.MethodGet, "/extract?format=epub", nil)
+	w := httptest.NewRecorder()
+	entry, _ := cache.Get("https://example.com/a")
+	s.writeResponse(w, r, parsedURL, entry.Article, true)
+
+	reGet, ok := cache.Get("https://example.com/a")
+	if !ok {
+		t.Fatalf("expected cache entry to still be present")
+	}
+	cachedImg := dom.QuerySelector(reGet.Article.Node, "img")
+	src := dom.GetAttribute(cachedImg, "src")
+	if src != img.URL+"/cover.png" {
+		t.Fatalf("expected cached article.Node to be untouched by the epub export, got src %q", src)
+	}
+}
+
+func imgServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("fake-png-bytes"))
+	}))
+}
@@ -0,0 +1,41 @@
+// Command readability-server exposes readability extraction as a small
+// JSON/HTML/EPUB HTTP API, so other applications can use the library
+// without linking Go.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+)
+
+func main() {
+	var (
+		addr          = flag.String("addr", ":8080", "address to listen on")
+		cacheSize     = flag.Int("cache-size", 1000, "number of extracted articles to keep in the in-memory cache")
+		rateLimit     = flag.Float64("rate-limit", 2, "max requests per second per origin host")
+		rateBurst     = flag.Int("rate-burst", 5, "burst size for the per-host rate limiter")
+		clientTimeout = flag.Duration("client-timeout", 30*time.Second, "timeout for upstream fetches")
+	)
+	flag.Parse()
+
+	srv := newServer(serverConfig{
+		cache:   newArticleCache(*cacheSize),
+		limiter: newHostLimiter(*rateLimit, *rateBurst),
+		httpClient: &http.Client{
+			Timeout:       *clientTimeout,
+			CheckRedirect: blockInternalRedirects,
+			Transport:     newSafeTransport(),
+		},
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/extract", srv.handleExtract)
+	mux.Handle("/metrics", metricsHandler())
+
+	log.Printf("readability-server listening on %s\n", *addr)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		log.Fatalf("server failed: %v\n", err)
+	}
+}
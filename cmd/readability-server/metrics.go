@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "readability_server_requests_total",
+		Help: "Total number of /extract requests, labeled by outcome.",
+	}, []string{"outcome"})
+
+	extractionDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "readability_server_extraction_seconds",
+		Help:    "Time spent fetching and extracting an article.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	cacheHitsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "readability_server_cache_total",
+		Help: "Cache lookups, labeled by hit or miss.",
+	}, []string{"result"})
+
+	hostErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "readability_server_host_errors_total",
+		Help: "Extraction errors per origin host.",
+	}, []string{"host"})
+)
+
+func init() {
+	prometheus.MustRegister(requestsTotal, extractionDuration, cacheHitsTotal, hostErrorsTotal)
+}
+
+func metricsHandler() http.Handler {
+	return promhttp.Handler()
+}
+
+// maxHostLabels bounds how many distinct "host" label values
+// hostErrorsTotal will ever report. Since host comes straight from the
+// caller-supplied URL, labeling by it without a cap lets a caller with
+// an endless supply of hostnames grow the metric's cardinality without
+// bound; past the cap, further hosts are folded into a single "other"
+// bucket.
+const maxHostLabels = 1000
+
+var hostLabels = struct {
+	mu   sync.Mutex
+	seen map[string]struct{}
+}{seen: make(map[string]struct{})}
+
+// boundedHostLabel returns host as-is if it has been seen before or fewer
+// than maxHostLabels distinct hosts have been seen so far; any new host
+// past the cap gets "other".
+func boundedHostLabel(host string) string {
+	hostLabels.mu.Lock()
+	defer hostLabels.mu.Unlock()
+
+	if _, ok := hostLabels.seen[host]; ok {
+		return host
+	}
+	if len(hostLabels.seen) >= maxHostLabels {
+		return "other"
+	}
+	hostLabels.seen[host] = struct{}{}
+	return host
+}
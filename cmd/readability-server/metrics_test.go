@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBoundedHostLabelCapsCardinality(t *testing.T) {
+	hostLabels.mu.Lock()
+	hostLabels.seen = make(map[string]struct{})
+	hostLabels.mu.Unlock()
+
+	for i := 0; i < maxHostLabels+10; i++ {
+		boundedHostLabel(fmt.Sprintf("host-%d.example.com", i))
+	}
+
+	if got := boundedHostLabel(fmt.Sprintf("host-%d.example.com", maxHostLabels+5)); got != "other" {
+		t.Fatalf("expected a host past the cap to fold into \"other\", got %q", got)
+	}
+	if got := boundedHostLabel("host-0.example.com"); got != "host-0.example.com" {
+		t.Fatalf("expected an already-seen host to keep its own label, got %q", got)
+	}
+}
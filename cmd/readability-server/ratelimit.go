@@ -0,0 +1,69 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// maxTrackedHosts bounds how many distinct hosts hostLimiter keeps a
+// rate.Limiter for at once. Without a cap, a caller who hits /extract with
+// a different ?url= host on every request could grow the limiter map
+// without bound.
+const maxTrackedHosts = 10000
+
+// hostLimiter hands out a token-bucket rate.Limiter per origin host, so
+// one slow or abusive origin can't starve requests for every other host.
+// It keeps at most maxTrackedHosts limiters, evicting the least recently
+// used host to make room for a new one.
+type hostLimiter struct {
+	mu       sync.Mutex
+	ll       *list.List
+	limiters map[string]*list.Element
+	r        rate.Limit
+	burst    int
+}
+
+type hostLimiterEntry struct {
+	host    string
+	limiter *rate.Limiter
+}
+
+func newHostLimiter(ratePerSecond float64, burst int) *hostLimiter {
+	return &hostLimiter{
+		ll:       list.New(),
+		limiters: make(map[string]*list.Element),
+		r:        rate.Limit(ratePerSecond),
+		burst:    burst,
+	}
+}
+
+// Allow reports whether a request to host is currently permitted.
+func (h *hostLimiter) Allow(host string) bool {
+	return h.limiterFor(host).Allow()
+}
+
+func (h *hostLimiter) limiterFor(host string) *rate.Limiter {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if el, ok := h.limiters[host]; ok {
+		h.ll.MoveToFront(el)
+		return el.Value.(*hostLimiterEntry).limiter
+	}
+
+	l := rate.NewLimiter(h.r, h.burst)
+	el := h.ll.PushFront(&hostLimiterEntry{host: host, limiter: l})
+	h.limiters[host] = el
+
+	if h.ll.Len() > maxTrackedHosts {
+		oldest := h.ll.Back()
+		if oldest != nil {
+			h.ll.Remove(oldest)
+			delete(h.limiters, oldest.Value.(*hostLimiterEntry).host)
+		}
+	}
+
+	return l
+}
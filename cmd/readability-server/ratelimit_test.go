@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestHostLimiterEvictsLeastRecentlyUsed(t *testing.T) {
+	h := newHostLimiter(1, 1)
+
+	for i := 0; i < maxTrackedHosts+10; i++ {
+		h.Allow(fmt.Sprintf("host-%d.example.com", i))
+	}
+
+	if got := len(h.limiters); got != maxTrackedHosts {
+		t.Fatalf("expected limiter map to stay capped at %d, got %d", maxTrackedHosts, got)
+	}
+
+	if _, ok := h.limiters["host-0.example.com"]; ok {
+		t.Fatalf("expected the earliest host to have been evicted")
+	}
+	if _, ok := h.limiters[fmt.Sprintf("host-%d.example.com", maxTrackedHosts+9)]; !ok {
+		t.Fatalf("expected the most recent host to still be tracked")
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"net"
+	nurl "net/url"
+	"strings"
+	"testing"
+)
+
+func TestValidateOutboundURLRejectsInternalAddresses(t *testing.T) {
+	cases := []string{
+		"http://127.0.0.1/",
+		"http://localhost/",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.5/",
+		"http://[::1]/",
+		"file:///etc/passwd",
+	}
+
+	for _, raw := range cases {
+		u, err := nurl.ParseRequestURI(raw)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", raw, err)
+		}
+		if err := validateOutboundURL(u); err == nil {
+			t.Errorf("expected %q to be rejected", raw)
+		}
+	}
+}
+
+func TestValidateOutboundURLAllowsPublicAddress(t *testing.T) {
+	u, err := nurl.ParseRequestURI("http://93.184.216.34/")
+	if err != nil {
+		t.Fatalf("failed to parse url: %v", err)
+	}
+	if err := validateOutboundURL(u); err != nil {
+		t.Fatalf("expected public address to be allowed, got error: %v", err)
+	}
+}
+
+func TestSafeDialContextRejectsInternalAddress(t *testing.T) {
+	dial := safeDialContext(&net.Dialer{})
+
+	_, err := dial(context.Background(), "tcp", net.JoinHostPort("127.0.0.1", "80"))
+	if err == nil {
+		t.Fatalf("expected dial to a loopback address to be rejected")
+	}
+	if !strings.Contains(err.Error(), "internal address") {
+		t.Fatalf("expected an internal-address error, got: %v", err)
+	}
+}
+
+func TestSafeDialContextRejectsHostnameResolvingToLoopback(t *testing.T) {
+	srv := net.JoinHostPort("127.0.0.1", "0")
+	l, err := net.Listen("tcp", srv)
+	if err != nil {
+		t.Fatalf("failed to start listener: %v", err)
+	}
+	defer l.Close()
+
+	// Even though "localhost" resolves to the loopback address we're
+	// listening on, it must still be rejected: safeDialContext validates
+	// every address the host resolves to, not just the one it happens to
+	// dial.
+	dial := safeDialContext(&net.Dialer{})
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	_, err = dial(context.Background(), "tcp", net.JoinHostPort("localhost", port))
+	if err == nil {
+		t.Fatalf("expected dial via hostname resolving to loopback to be rejected")
+	}
+}
@@ -0,0 +1,90 @@
+package readability
+
+import (
+	"fmt"
+	nurl "net/url"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// unwantedTags are stripped from the document before scoring candidates,
+// since they're never part of the readable article body.
+var unwantedTags = []string{"script", "style", "noscript", "nav", "header", "footer", "aside", "form", "iframe"}
+
+// candidateSelector lists the elements considered when looking for the
+// container that holds the article body.
+const candidateSelector = "article, main, section, div"
+
+// extractContent picks the element most likely to hold the article body
+// (by total paragraph text length), rewrites its relative links/images
+// against pageURL, and returns it along with its plain-text content.
+func extractContent(doc *html.Node, pageURL *nurl.URL) (*html.Node, string, error) {
+	dom.RemoveNodes(dom.GetAllNodesWithTag(doc, unwantedTags...), nil)
+
+	candidate := bestCandidate(doc)
+	if candidate == nil {
+		return nil, "", fmt.Errorf("no readable content found")
+	}
+
+	resolveLinks(candidate, pageURL)
+
+	return candidate, strings.TrimSpace(dom.TextContent(candidate)), nil
+}
+
+// bestCandidate returns the element under doc with the highest paragraph
+// text score, falling back to <body> if nothing scores above zero.
+func bestCandidate(doc *html.Node) *html.Node {
+	var best *html.Node
+	var bestScore int
+
+	for _, node := range dom.QuerySelectorAll(doc, candidateSelector) {
+		score := scoreOf(node)
+		if score > bestScore {
+			best = node
+			bestScore = score
+		}
+	}
+
+	if best != nil {
+		return best
+	}
+	return dom.QuerySelector(doc, "body")
+}
+
+// scoreOf approximates how likely node is to be the article body: the
+// total length of its paragraph text, with a bonus for paragraph count
+// so that many short paragraphs beat one long sidebar blurb.
+func scoreOf(node *html.Node) int {
+	paragraphs := dom.QuerySelectorAll(node, "p")
+	if len(paragraphs) == 0 {
+		return 0
+	}
+
+	var textLength int
+	for _, p := range paragraphs {
+		textLength += len(strings.TrimSpace(dom.TextContent(p)))
+	}
+
+	return textLength + len(paragraphs)*25
+}
+
+// resolveLinks rewrites every relative <a href> and <img src> under node
+// to an absolute URL, so the extracted content is portable on its own.
+func resolveLinks(node *html.Node, baseURL *nurl.URL) {
+	if baseURL == nil {
+		return
+	}
+
+	for _, a := range dom.QuerySelectorAll(node, "a") {
+		if href := dom.GetAttribute(a, "href"); href != "" {
+			dom.SetAttribute(a, "href", resolveMaybe(baseURL, href))
+		}
+	}
+	for _, img := range dom.QuerySelectorAll(node, "img") {
+		if src := dom.GetAttribute(img, "src"); src != "" {
+			dom.SetAttribute(img, "src", resolveMaybe(baseURL, src))
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Package export renders a readability.Article into e-reader formats.
+//
+// WriteEPUB produces a spec-compliant EPUB 3 file directly. WriteMOBI
+// reuses the EPUB output and shells out to a locally installed converter
+// (kindlegen or ebook-convert) since MOBI has no practical pure-Go writer.
+package export
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	nurl "net/url"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+
+	"github.com/vissong/go-readability"
+)
+
+// Options configures how an Article is rendered to an e-reader file.
+type Options struct {
+	// Title overrides article.Title when non-empty.
+	Title string
+	// Author overrides article.Byline when non-empty.
+	Author string
+	// Language overrides article.Language when non-empty. Defaults to "en".
+	Language string
+	// CoverImageURL, if set, is downloaded and used as the EPUB cover.
+	CoverImageURL string
+	// BaseURL resolves relative <img src>/<a href> found in article.Content.
+	// It should usually be the URL the article was extracted from.
+	BaseURL *nurl.URL
+	// Identifier is the book's unique identifier (urn:uuid:... by default).
+	Identifier string
+}
+
+func (o Options) title(article readability.Article) string {
+	if o.Title != "" {
+		return o.Title
+	}
+	if article.Title != "" {
+		return article.Title
+	}
+	return "Untitled"
+}
+
+func (o Options) author(article readability.Article) string {
+	if o.Author != "" {
+		return o.Author
+	}
+	return article.Byline
+}
+
+func (o Options) language(article readability.Article) string {
+	if o.Language != "" {
+		return o.Language
+	}
+	if article.Language != "" {
+		return article.Language
+	}
+	return "en"
+}
+
+// WriteEPUB writes article as a valid EPUB 3 file to w.
+func WriteEPUB(w io.Writer, article readability.Article, opts Options) error {
+	id := opts.Identifier
+	if id == "" {
+		id = newUUID()
+	}
+
+	zw := zip.NewWriter(w)
+	defer zw.Close()
+
+	// The mimetype entry must be the first file in the archive and must be
+	// stored, not deflated, or some readers will refuse to open the book.
+	mimeWriter, err := zw.CreateHeader(&zip.FileHeader{
+		Name:   "mimetype",
+		Method: zip.Store,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write mimetype entry: %v", err)
+	}
+	if _, err = io.WriteString(mimeWriter, "application/epub+zip"); err != nil {
+		return fmt.Errorf("failed to write mimetype entry: %v", err)
+	}
+
+	if err = writeFile(zw, "META-INF/container.xml", containerXML()); err != nil {
+		return err
+	}
+
+	// fetchImages rewrites src/srcset attributes in place; operate on a
+	// clone so exporting (or re-exporting) an Article never mutates the
+	// caller's own copy of article.Node.
+	var node *html.Node
+	if article.Node != nil {
+		node = dom.Clone(article.Node, true)
+
+		// Images are resolved against opts.BaseURL by fetchImages below;
+		// links are resolved here.
+		for _, a := range dom.QuerySelectorAll(node, "a") {
+			if href := dom.GetAttribute(a, "href"); href != "" {
+				dom.SetAttribute(a, "href", resolveURL(opts.BaseURL, href))
+			}
+		}
+	}
+
+	images, err := fetchImages(node, opts.BaseURL, opts.CoverImageURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch article images: %v", err)
+	}
+	for _, img := range images {
+		if err = writeBytes(zw, "OEBPS/"+img.ArchivePath, img.Data); err != nil {
+			return err
+		}
+	}
+
+	xhtml, err := renderXHTML(opts.title(article), opts.language(article), node)
+	if err != nil {
+		return fmt.Errorf("failed to render article content: %v", err)
+	}
+	if err = writeFile(zw, "OEBPS/content.xhtml", xhtml); err != nil {
+		return err
+	}
+
+	var coverImageID string
+	for _, img := range images {
+		if img.IsCover {
+			coverImageID = img.ID
+			break
+		}
+	}
+
+	if err = writeFile(zw, "OEBPS/nav.xhtml", navXHTML(opts.title(article))); err != nil {
+		return err
+	}
+	if err = writeFile(zw, "OEBPS/toc.ncx", tocNCX(id, opts.title(article))); err != nil {
+		return err
+	}
+	if err = writeFile(zw, "OEBPS/content.opf", packageOPF(packageInfo{
+		ID:           id,
+		Title:        opts.title(article),
+		Author:       opts.author(article),
+		Language:     opts.language(article),
+		CoverImageID: coverImageID,
+		Images:       images,
+	})); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func writeFile(zw *zip.Writer, name, content string) error {
+	return writeBytes(zw, name, []byte(content))
+}
+
+func writeBytes(zw *zip.Writer, name string, content []byte) error {
+	fw, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", name, err)
+	}
+	if _, err = fw.Write(content); err != nil {
+		return fmt.Errorf("failed to write %s: %v", name, err)
+	}
+	return nil
+}
+
+func containerXML() string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+}
@@ -0,0 +1,48 @@
+package export
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+
+	readability "github.com/vissong/go-readability"
+)
+
+func TestWriteEPUBDoesNotMutateArticleNode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("fake-png-bytes"))
+	}))
+	defer srv.Close()
+
+	doc, err := dom.Parse(strings.NewReader(`<html><body><article>
+		<p>intro</p>
+		<img src="` + srv.URL + `/cover.png">
+	</article></body></html>`))
+	if err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+	article := readability.Article{Node: dom.QuerySelector(doc, "article")}
+
+	var buf bytes.Buffer
+	if err := WriteEPUB(&buf, article, Options{}); err != nil {
+		t.Fatalf("first WriteEPUB returned error: %v", err)
+	}
+
+	img := dom.QuerySelector(article.Node, "img")
+	src := dom.GetAttribute(img, "src")
+	if src != srv.URL+"/cover.png" {
+		t.Fatalf("expected article.Node's img src to be untouched after export, got %q", src)
+	}
+
+	// A second export of the same Article must succeed and fetch the image
+	// again rather than finding an already-rewritten local path.
+	var buf2 bytes.Buffer
+	if err := WriteEPUB(&buf2, article, Options{}); err != nil {
+		t.Fatalf("second WriteEPUB returned error: %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	nurl "net/url"
+	"path"
+	"strconv"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// epubImage is an image that has been downloaded and embedded in the book.
+type epubImage struct {
+	ID          string
+	ArchivePath string // path relative to OEBPS/
+	MediaType   string
+	Data        []byte
+	IsCover     bool
+}
+
+// fetchImages walks node for <img>/<picture><source> elements, downloads
+// every referenced image, rewrites the node's src attributes to point at
+// the local OEBPS/images copy, and returns the fetched images so the
+// caller can add them to the archive and the OPF manifest. When
+// coverImageURL is set it is fetched as well and flagged as the cover.
+func fetchImages(node *html.Node, baseURL *nurl.URL, coverImageURL string) ([]epubImage, error) {
+	if node == nil {
+		return nil, nil
+	}
+
+	seen := map[string]int{} // resolved URL -> index into images
+	var images []epubImage
+
+	fetchOne := func(rawURL string) (int, error) {
+		resolved := resolveURL(baseURL, rawURL)
+		if idx, ok := seen[resolved]; ok {
+			return idx, nil
+		}
+
+		resp, err := http.Get(resolved)
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch image %s: %v", resolved, err)
+		}
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read image %s: %v", resolved, err)
+		}
+
+		mediaType := resp.Header.Get("Content-Type")
+		if mediaType == "" {
+			mediaType = mime.TypeByExtension(path.Ext(resolved))
+		}
+		if mediaType == "" {
+			mediaType = "image/jpeg"
+		}
+
+		ext := extensionForMediaType(mediaType)
+		id := "img" + strconv.Itoa(len(images)+1)
+		images = append(images, epubImage{
+			ID:          id,
+			ArchivePath: "images/" + id + ext,
+			MediaType:   mediaType,
+			Data:        data,
+		})
+
+		idx := len(images) - 1
+		seen[resolved] = idx
+		return idx, nil
+	}
+
+	for _, img := range dom.QuerySelectorAll(node, "img") {
+		src := dom.GetAttribute(img, "src")
+		if src == "" {
+			continue
+		}
+		idx, err := fetchOne(src)
+		if err != nil {
+			return nil, err
+		}
+		dom.SetAttribute(img, "src", images[idx].ArchivePath)
+	}
+
+	for _, source := range dom.QuerySelectorAll(node, "picture source") {
+		srcset := dom.GetAttribute(source, "srcset")
+		if srcset == "" {
+			continue
+		}
+		idx, err := fetchOne(firstSrcsetURL(srcset))
+		if err != nil {
+			return nil, err
+		}
+		dom.SetAttribute(source, "srcset", images[idx].ArchivePath)
+	}
+
+	if coverImageURL != "" {
+		idx, err := fetchOne(coverImageURL)
+		if err != nil {
+			return nil, err
+		}
+		images[idx].IsCover = true
+	}
+
+	return images, nil
+}
+
+func resolveURL(baseURL *nurl.URL, rawURL string) string {
+	ref, err := nurl.Parse(rawURL)
+	if err != nil || baseURL == nil {
+		return rawURL
+	}
+	return baseURL.ResolveReference(ref).String()
+}
+
+// firstSrcsetURL returns the URL of the first candidate in a srcset
+// attribute, ignoring its width/density descriptor.
+func firstSrcsetURL(srcset string) string {
+	for i := 0; i < len(srcset); i++ {
+		if srcset[i] == ',' {
+			srcset = srcset[:i]
+			break
+		}
+	}
+	for i := 0; i < len(srcset); i++ {
+		if srcset[i] == ' ' {
+			return srcset[:i]
+		}
+	}
+	return srcset
+}
+
+func extensionForMediaType(mediaType string) string {
+	switch mediaType {
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	case "image/svg+xml":
+		return ".svg"
+	default:
+		return ".jpg"
+	}
+}
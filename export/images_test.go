@@ -0,0 +1,48 @@
+package export
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+func TestFetchImagesReusedCoverIsFlagged(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("fake-png-bytes"))
+	}))
+	defer srv.Close()
+
+	doc, err := dom.Parse(strings.NewReader(`<html><body>
+		<p>intro</p>
+		<img src="` + srv.URL + `/cover.png">
+		<p>more text with another image below</p>
+		<img src="` + srv.URL + `/other.png">
+	</body></html>`))
+	if err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+
+	node := dom.QuerySelector(doc, "body")
+
+	// The cover image URL is also referenced inline in the article body,
+	// which is the common og:image-reused-as-lead-image case.
+	images, err := fetchImages(node, nil, srv.URL+"/cover.png")
+	if err != nil {
+		t.Fatalf("fetchImages returned error: %v", err)
+	}
+
+	var coverCount int
+	for _, img := range images {
+		if img.IsCover {
+			coverCount++
+		}
+	}
+
+	if coverCount != 1 {
+		t.Fatalf("expected exactly one cover image, got %d (images: %+v)", coverCount, images)
+	}
+}
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/vissong/go-readability"
+)
+
+// WriteMOBI renders article as an EPUB to a temporary file and converts it
+// to MOBI using whichever of kindlegen or ebook-convert is found on PATH.
+// kindlegen is tried first since it's the format's reference converter;
+// ebook-convert (Calibre) is used as a fallback.
+func WriteMOBI(outPath string, article readability.Article, opts Options) error {
+	converter, useKindlegen, err := findMOBIConverter()
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "go-readability-mobi")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	epubPath := filepath.Join(tmpDir, "article.epub")
+	epubFile, err := os.Create(epubPath)
+	if err != nil {
+		return fmt.Errorf("failed to create intermediate epub: %v", err)
+	}
+	if err = WriteEPUB(epubFile, article, opts); err != nil {
+		epubFile.Close()
+		return fmt.Errorf("failed to render intermediate epub: %v", err)
+	}
+	if err = epubFile.Close(); err != nil {
+		return fmt.Errorf("failed to close intermediate epub: %v", err)
+	}
+
+	var cmd *exec.Cmd
+	if useKindlegen {
+		// kindlegen writes its output next to the input file, under the
+		// bare file name given with -o.
+		cmd = exec.Command(converter, epubPath, "-o", filepath.Base(outPath))
+		cmd.Dir = tmpDir
+	} else {
+		cmd = exec.Command(converter, epubPath, outPath)
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to convert to mobi: %v: %s", err, output)
+	}
+
+	if useKindlegen {
+		generated := filepath.Join(tmpDir, filepath.Base(outPath))
+		if err = os.Rename(generated, outPath); err != nil {
+			return fmt.Errorf("failed to move generated mobi: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func findMOBIConverter() (path string, isKindlegen bool, err error) {
+	if path, err := exec.LookPath("kindlegen"); err == nil {
+		return path, true, nil
+	}
+	if path, err := exec.LookPath("ebook-convert"); err == nil {
+		return path, false, nil
+	}
+	return "", false, fmt.Errorf("neither kindlegen nor ebook-convert was found on PATH")
+}
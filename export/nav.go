@@ -0,0 +1,39 @@
+package export
+
+import (
+	"fmt"
+	"html"
+)
+
+func navXHTML(title string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head><title>%s</title></head>
+<body>
+  <nav epub:type="toc">
+    <ol>
+      <li><a href="content.xhtml">%s</a></li>
+    </ol>
+  </nav>
+</body>
+</html>
+`, html.EscapeString(title), html.EscapeString(title))
+}
+
+func tocNCX(id, title string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+  <head>
+    <meta name="dtb:uid" content="%s"/>
+  </head>
+  <docTitle><text>%s</text></docTitle>
+  <navMap>
+    <navPoint id="navpoint-1" playOrder="1">
+      <navLabel><text>%s</text></navLabel>
+      <content src="content.xhtml"/>
+    </navPoint>
+  </navMap>
+</ncx>
+`, id, html.EscapeString(title), html.EscapeString(title))
+}
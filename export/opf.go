@@ -0,0 +1,52 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// packageInfo carries the metadata needed to render content.opf.
+type packageInfo struct {
+	ID           string
+	Title        string
+	Author       string
+	Language     string
+	CoverImageID string
+	Images       []epubImage
+}
+
+func packageOPF(info packageInfo) string {
+	var meta strings.Builder
+	if info.CoverImageID != "" {
+		fmt.Fprintf(&meta, `    <meta name="cover" content="%s"/>`+"\n", info.CoverImageID)
+	}
+
+	var manifest strings.Builder
+	manifest.WriteString(`    <item id="content" href="content.xhtml" media-type="application/xhtml+xml"/>` + "\n")
+	manifest.WriteString(`    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>` + "\n")
+	manifest.WriteString(`    <item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>` + "\n")
+	for _, img := range info.Images {
+		properties := ""
+		if img.IsCover {
+			properties = ` properties="cover-image"`
+		}
+		fmt.Fprintf(&manifest, `    <item id="%s" href="%s" media-type="%s"%s/>`+"\n", img.ID, img.ArchivePath, img.MediaType, properties)
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="BookId">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="BookId">%s</dc:identifier>
+    <dc:title>%s</dc:title>
+    <dc:creator>%s</dc:creator>
+    <dc:language>%s</dc:language>
+%s  </metadata>
+  <manifest>
+%s  </manifest>
+  <spine toc="ncx">
+    <itemref idref="content"/>
+  </spine>
+</package>
+`, info.ID, html.EscapeString(info.Title), html.EscapeString(info.Author), info.Language, meta.String(), manifest.String())
+}
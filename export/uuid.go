@@ -0,0 +1,20 @@
+package export
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// newUUID generates a random (v4) UUID for use as an EPUB identifier when
+// the caller doesn't supply one.
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "urn:uuid:00000000-0000-4000-8000-000000000000"
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
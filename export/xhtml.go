@@ -0,0 +1,73 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+
+	gohtml "golang.org/x/net/html"
+
+	"github.com/go-shiori/dom"
+)
+
+// voidElements is the set of HTML5 elements that must be self-closed to
+// be valid XHTML (<br/> rather than <br>).
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+// renderXHTML wraps a clone of node's children in a minimal XHTML (HTML5
+// in XML syntax) document suitable for an EPUB 3 content document.
+func renderXHTML(title, language string, node *gohtml.Node) (string, error) {
+	clone := dom.Clone(node, true)
+
+	var buf bytes.Buffer
+	renderChildren(&buf, clone)
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="%s" xml:lang="%s">
+<head>
+  <meta charset="UTF-8"/>
+  <title>%s</title>
+</head>
+<body>
+<article>
+%s
+</article>
+</body>
+</html>
+`, language, language, html.EscapeString(title), buf.String()), nil
+}
+
+func renderChildren(buf *bytes.Buffer, node *gohtml.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		renderNode(buf, child)
+	}
+}
+
+func renderNode(buf *bytes.Buffer, node *gohtml.Node) {
+	switch node.Type {
+	case gohtml.TextNode:
+		buf.WriteString(html.EscapeString(node.Data))
+	case gohtml.ElementNode:
+		tag := strings.ToLower(node.Data)
+		buf.WriteByte('<')
+		buf.WriteString(tag)
+		for _, attr := range node.Attr {
+			fmt.Fprintf(buf, ` %s="%s"`, attr.Key, html.EscapeString(attr.Val))
+		}
+		if voidElements[tag] {
+			buf.WriteString("/>")
+			return
+		}
+		buf.WriteByte('>')
+		renderChildren(buf, node)
+		fmt.Fprintf(buf, "</%s>", tag)
+	default:
+		renderChildren(buf, node)
+	}
+}
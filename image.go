@@ -0,0 +1,286 @@
+package readability
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	nurl "net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// ImageMode controls what WithImageFetcher does with images found in an
+// extracted article.
+type ImageMode int
+
+const (
+	// ImageModeSkip leaves <img>/<picture>/srcset references untouched.
+	// This is the default.
+	ImageModeSkip ImageMode = iota
+	// ImageModeInlineBase64 replaces each image src with a data: URI.
+	ImageModeInlineBase64
+	// ImageModeSideload writes image bytes to the configured ImageSink and
+	// rewrites src to the path the sink returns.
+	ImageModeSideload
+)
+
+// ImageAsset describes one image that was discovered and fetched while
+// processing an article.
+type ImageAsset struct {
+	URL         string
+	Path        string // data URI, or sideloaded path, depending on ImageMode
+	ContentType string
+	Size        int
+}
+
+// ImageFetcher fetches the bytes and content type of a single image URL.
+// Implementations should respect ctx cancellation.
+type ImageFetcher interface {
+	Fetch(ctx context.Context, url string) (data []byte, contentType string, err error)
+}
+
+// ImageSink receives sideloaded image bytes and returns the path or URL
+// that should replace the image's src attribute.
+type ImageSink interface {
+	Write(path string, data []byte) (string, error)
+}
+
+// DefaultImageFetcher fetches images over HTTP(S) using client. If client
+// is nil, http.DefaultClient is used.
+type DefaultImageFetcher struct {
+	Client *http.Client
+}
+
+// Fetch implements ImageFetcher.
+func (f DefaultImageFetcher) Fetch(ctx context.Context, url string) ([]byte, string, error) {
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", fmt.Errorf("status code %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, resp.Header.Get("Content-Type"), nil
+}
+
+// imageConfig holds the settings applied by WithImageFetcher/WithImageMode.
+type imageConfig struct {
+	mode         ImageMode
+	fetcher      ImageFetcher
+	sink         ImageSink
+	maxWorkers   int
+	maxImageSize int64
+}
+
+// WithImageFetcher enables image processing for a FromURLWithOptions call
+// and sets the fetcher used to download each image. Use WithImageMode to
+// choose what happens to the downloaded bytes.
+func WithImageFetcher(fetcher ImageFetcher) Option {
+	return func(c *fetchConfig) {
+		c.images.fetcher = fetcher
+		if c.images.maxWorkers == 0 {
+			c.images.maxWorkers = 4
+		}
+	}
+}
+
+// WithImageMode selects how images are handled once fetched: inlined as
+// base64 data URIs, or sideloaded through WithImageSink. Defaults to
+// ImageModeSkip, which leaves the article untouched.
+func WithImageMode(mode ImageMode) Option {
+	return func(c *fetchConfig) { c.images.mode = mode }
+}
+
+// WithImageSink sets the sink used when WithImageMode(ImageModeSideload)
+// is active.
+func WithImageSink(sink ImageSink) Option {
+	return func(c *fetchConfig) { c.images.sink = sink }
+}
+
+// WithImageWorkers bounds how many images are fetched concurrently.
+// Defaults to 4.
+func WithImageWorkers(n int) Option {
+	return func(c *fetchConfig) { c.images.maxWorkers = n }
+}
+
+// WithMaxImageBytes skips any image larger than n bytes, leaving it
+// untouched. The size is checked on the bytes the fetcher returns, so
+// the fetcher still downloads the whole image. A value <= 0 means
+// unlimited.
+func WithMaxImageBytes(n int64) Option {
+	return func(c *fetchConfig) { c.images.maxImageSize = n }
+}
+
+// processImages walks article.Node for <img> (including its srcset, if
+// any) and <picture>/<source srcset> references, fetches each one
+// (bounded by cfg.maxWorkers and cfg.maxImageSize) relative to baseURL,
+// rewrites the node in place according to cfg.mode, and fills in
+// article.Images. ctx bounds every fetch, so cancelling or timing out
+// the caller's context stops in-flight image fetches too.
+func processImages(ctx context.Context, article *Article, baseURL *nurl.URL, cfg imageConfig) error {
+	if cfg.mode == ImageModeSkip || cfg.fetcher == nil || article.Node == nil {
+		return nil
+	}
+	if cfg.mode == ImageModeSideload && cfg.sink == nil {
+		return fmt.Errorf("image mode is ImageModeSideload but no ImageSink was configured")
+	}
+
+	type job struct {
+		node  *html.Node
+		attrs []string // all of these are rewritten to the same fetched asset
+		url   string
+	}
+
+	var jobs []job
+	for _, img := range dom.QuerySelectorAll(article.Node, "img") {
+		src := dom.GetAttribute(img, "src")
+		if src == "" {
+			continue
+		}
+
+		// srcset takes priority over src in a compliant renderer, so a
+		// stale remote srcset left behind would mean the fetched image is
+		// never actually shown. Point both at the same fetched asset.
+		attrs := []string{"src"}
+		if dom.HasAttribute(img, "srcset") {
+			attrs = append(attrs, "srcset")
+		}
+		jobs = append(jobs, job{img, attrs, src})
+	}
+	for _, source := range dom.QuerySelectorAll(article.Node, "picture source") {
+		if srcset := dom.GetAttribute(source, "srcset"); srcset != "" {
+			jobs = append(jobs, job{source, []string{"srcset"}, firstSrcsetURL(srcset)})
+		}
+	}
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	workers := cfg.maxWorkers
+	if workers < 1 {
+		workers = 4
+	}
+
+	var (
+		mu     sync.Mutex
+		assets []ImageAsset
+		wg     sync.WaitGroup
+		sem    = make(chan struct{}, workers)
+		seq    uint64
+	)
+
+	for _, j := range jobs {
+		wg.Add(1)
+		go func(j job) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			resolved := resolveImageURL(baseURL, j.url)
+
+			data, contentType, err := cfg.fetcher.Fetch(ctx, resolved)
+			if err != nil {
+				return
+			}
+			if cfg.maxImageSize > 0 && int64(len(data)) > cfg.maxImageSize {
+				return
+			}
+
+			var rewritten string
+			switch cfg.mode {
+			case ImageModeInlineBase64:
+				rewritten = toDataURI(contentType, data)
+			case ImageModeSideload:
+				n := atomic.AddUint64(&seq, 1)
+				path, sinkErr := cfg.sink.Write(sideloadName(resolved, n), data)
+				if sinkErr != nil {
+					return
+				}
+				rewritten = path
+			default:
+				return
+			}
+
+			for _, attr := range j.attrs {
+				dom.SetAttribute(j.node, attr, rewritten)
+			}
+
+			mu.Lock()
+			assets = append(assets, ImageAsset{
+				URL:         resolved,
+				Path:        rewritten,
+				ContentType: contentType,
+				Size:        len(data),
+			})
+			mu.Unlock()
+		}(j)
+	}
+	wg.Wait()
+
+	article.Images = assets
+	return nil
+}
+
+func resolveImageURL(baseURL *nurl.URL, rawURL string) string {
+	ref, err := nurl.Parse(rawURL)
+	if err != nil || baseURL == nil {
+		return rawURL
+	}
+	return baseURL.ResolveReference(ref).String()
+}
+
+// firstSrcsetURL returns the URL of the first candidate in a srcset
+// attribute, ignoring its width/density descriptor.
+func firstSrcsetURL(srcset string) string {
+	if i := strings.IndexByte(srcset, ','); i >= 0 {
+		srcset = srcset[:i]
+	}
+	if i := strings.IndexByte(srcset, ' '); i >= 0 {
+		return srcset[:i]
+	}
+	return srcset
+}
+
+func toDataURI(contentType string, data []byte) string {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// sideloadName derives a filename for a sideloaded image. seq is a
+// per-call counter (not the image's byte size, which collides whenever
+// two distinct images happen to be the same length) that callers must
+// bump for every image they write, guaranteeing distinct names.
+func sideloadName(url string, seq uint64) string {
+	ext := ".bin"
+	if i := strings.LastIndexByte(url, '.'); i >= 0 && i > strings.LastIndexByte(url, '/') {
+		ext = url[i:]
+	}
+	return "img-" + strconv.FormatUint(seq, 10) + ext
+}
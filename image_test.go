@@ -0,0 +1,54 @@
+package readability
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+type fakeImageFetcher struct{}
+
+func (fakeImageFetcher) Fetch(ctx context.Context, url string) ([]byte, string, error) {
+	return []byte("fake-bytes"), "image/png", nil
+}
+
+func TestProcessImagesRewritesImgSrcset(t *testing.T) {
+	doc, err := dom.Parse(strings.NewReader(
+		`<html><body><img src="/a.png" srcset="/a.png 1x, /a-2x.png 2x"></body></html>`,
+	))
+	if err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+
+	article := &Article{Node: dom.QuerySelector(doc, "body")}
+	cfg := imageConfig{mode: ImageModeInlineBase64, fetcher: fakeImageFetcher{}}
+
+	if err := processImages(context.Background(), article, nil, cfg); err != nil {
+		t.Fatalf("processImages returned error: %v", err)
+	}
+
+	img := dom.QuerySelector(article.Node, "img")
+	src := dom.GetAttribute(img, "src")
+	srcset := dom.GetAttribute(img, "srcset")
+
+	if !strings.HasPrefix(src, "data:image/png;base64,") {
+		t.Fatalf("expected src to be inlined, got %q", src)
+	}
+	if srcset != src {
+		t.Fatalf("expected stale srcset to be overwritten with the fetched asset, got %q", srcset)
+	}
+}
+
+func TestSideloadNameDistinguishesSameSizedImages(t *testing.T) {
+	a := sideloadName("https://example.com/a.png", 1)
+	b := sideloadName("https://example.com/b.png", 2)
+
+	if a == b {
+		t.Fatalf("expected distinct names for different sequence numbers, got %q for both", a)
+	}
+	if !strings.HasSuffix(a, ".png") || !strings.HasSuffix(b, ".png") {
+		t.Fatalf("expected extension to be preserved, got %q and %q", a, b)
+	}
+}
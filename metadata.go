@@ -0,0 +1,86 @@
+package readability
+
+import (
+	"strings"
+
+	nurl "net/url"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// articleMetadata holds everything extracted from <head> and the page's
+// top-level structure, as opposed to the article body itself.
+type articleMetadata struct {
+	title    string
+	byline   string
+	excerpt  string
+	siteName string
+	image    string
+	favicon  string
+	language string
+}
+
+func extractMetadata(doc *html.Node, pageURL *nurl.URL) articleMetadata {
+	return articleMetadata{
+		title:    firstNonEmpty(metaContent(doc, `meta[property="og:title"]`), textContentOf(doc, "title")),
+		byline:   metaContent(doc, `meta[name="author"]`),
+		excerpt:  firstNonEmpty(metaContent(doc, `meta[name="description"]`), metaContent(doc, `meta[property="og:description"]`)),
+		siteName: metaContent(doc, `meta[property="og:site_name"]`),
+		image:    resolveMaybe(pageURL, metaContent(doc, `meta[property="og:image"]`)),
+		favicon:  resolveMaybe(pageURL, faviconHref(doc)),
+		language: htmlLang(doc),
+	}
+}
+
+func metaContent(doc *html.Node, selector string) string {
+	node := dom.QuerySelector(doc, selector)
+	if node == nil {
+		return ""
+	}
+	return strings.TrimSpace(dom.GetAttribute(node, "content"))
+}
+
+func textContentOf(doc *html.Node, selector string) string {
+	node := dom.QuerySelector(doc, selector)
+	if node == nil {
+		return ""
+	}
+	return strings.TrimSpace(dom.TextContent(node))
+}
+
+func faviconHref(doc *html.Node) string {
+	for _, rel := range []string{`link[rel="icon"]`, `link[rel="shortcut icon"]`} {
+		if node := dom.QuerySelector(doc, rel); node != nil {
+			return dom.GetAttribute(node, "href")
+		}
+	}
+	return ""
+}
+
+func htmlLang(doc *html.Node) string {
+	if root := dom.QuerySelector(doc, "html"); root != nil {
+		return dom.GetAttribute(root, "lang")
+	}
+	return ""
+}
+
+func resolveMaybe(baseURL *nurl.URL, rawURL string) string {
+	if rawURL == "" || baseURL == nil {
+		return rawURL
+	}
+	ref, err := nurl.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return baseURL.ResolveReference(ref).String()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
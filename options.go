@@ -0,0 +1,165 @@
+package readability
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	nurl "net/url"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/go-shiori/dom"
+)
+
+// Option configures a fetch performed by FromURLWithOptions.
+type Option func(*fetchConfig)
+
+type fetchConfig struct {
+	client       *http.Client
+	ctx          context.Context
+	headers      http.Header
+	maxBodyBytes int64
+	decompress   bool
+	cookieJar    http.CookieJar
+	images       imageConfig
+}
+
+// WithHTTPClient overrides the *http.Client used to fetch the page, e.g. to
+// set a custom Transport, proxy, or cookie jar.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *fetchConfig) { c.client = client }
+}
+
+// WithHeader sets an additional request header, such as a custom
+// User-Agent or Cookie. It may be called multiple times.
+func WithHeader(key, value string) Option {
+	return func(c *fetchConfig) { c.headers.Add(key, value) }
+}
+
+// WithCookieJar attaches jar to the HTTP client used for the request. It
+// is applied after every option has run, so it takes effect regardless
+// of whether it's passed before or after WithHTTPClient.
+func WithCookieJar(jar http.CookieJar) Option {
+	return func(c *fetchConfig) { c.cookieJar = jar }
+}
+
+// WithContext makes the request cancellable/timeout-able via ctx, instead
+// of (or in addition to) the client's own timeout.
+func WithContext(ctx context.Context) Option {
+	return func(c *fetchConfig) { c.ctx = ctx }
+}
+
+// WithMaxBodyBytes caps how many bytes of the response body are read,
+// guarding against unbounded or malicious responses. A value <= 0 means
+// unlimited.
+func WithMaxBodyBytes(n int64) Option {
+	return func(c *fetchConfig) { c.maxBodyBytes = n }
+}
+
+// WithDecompression transparently decodes gzip, deflate, or brotli
+// response bodies based on the Content-Encoding header. It is off by
+// default because Go's http.Transport already handles gzip automatically
+// unless an Accept-Encoding header is set explicitly.
+func WithDecompression(enabled bool) Option {
+	return func(c *fetchConfig) { c.decompress = enabled }
+}
+
+// FromURLWithOptions is a more configurable variant of FromURL. It fetches
+// pageURL, applies opts (custom client, headers, cookie jar, context,
+// body size cap, and/or transparent decompression), and then parses the
+// response the same way FromDocument does.
+func FromURLWithOptions(pageURL string, opts ...Option) (Article, error) {
+	cfg := &fetchConfig{
+		client:  &http.Client{Timeout: 30 * time.Second},
+		headers: make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.cookieJar != nil {
+		cfg.client.Jar = cfg.cookieJar
+	}
+
+	parsedURL, err := nurl.ParseRequestURI(pageURL)
+	if err != nil {
+		return Article{}, fmt.Errorf("failed to parse url: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, pageURL, nil)
+	if err != nil {
+		return Article{}, fmt.Errorf("failed to create request: %v", err)
+	}
+	if cfg.ctx != nil {
+		req = req.WithContext(cfg.ctx)
+	}
+	for key, values := range cfg.headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	resp, err := cfg.client.Do(req)
+	if err != nil {
+		return Article{}, fmt.Errorf("failed to fetch url: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return Article{}, fmt.Errorf("failed to fetch url: status code %d", resp.StatusCode)
+	}
+
+	body := resp.Body
+	if cfg.decompress {
+		body, err = decompress(resp.Header.Get("Content-Encoding"), body)
+		if err != nil {
+			return Article{}, fmt.Errorf("failed to decompress response: %v", err)
+		}
+	}
+
+	var reader io.Reader = body
+	if cfg.maxBodyBytes > 0 {
+		reader = io.LimitReader(reader, cfg.maxBodyBytes)
+	}
+
+	doc, err := dom.Parse(reader)
+	if err != nil {
+		return Article{}, fmt.Errorf("failed to parse page: %v", err)
+	}
+
+	article, err := FromDocument(doc, parsedURL)
+	if err != nil {
+		return Article{}, err
+	}
+
+	imageCtx := cfg.ctx
+	if imageCtx == nil {
+		imageCtx = context.Background()
+	}
+	if err = processImages(imageCtx, &article, parsedURL, cfg.images); err != nil {
+		return Article{}, fmt.Errorf("failed to process images: %v", err)
+	}
+
+	return article, nil
+}
+
+// decompress wraps body in the reader matching contentEncoding. Unknown or
+// empty encodings are returned unwrapped.
+func decompress(contentEncoding string, body io.ReadCloser) (io.ReadCloser, error) {
+	switch contentEncoding {
+	case "gzip":
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, err
+		}
+		return gz, nil
+	case "deflate":
+		return flate.NewReader(body), nil
+	case "br":
+		return io.NopCloser(brotli.NewReader(body)), nil
+	default:
+		return body, nil
+	}
+}
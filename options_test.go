@@ -0,0 +1,37 @@
+package readability
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"testing"
+)
+
+func TestWithCookieJarSurvivesBeforeWithHTTPClient(t *testing.T) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatalf("failed to create cookie jar: %v", err)
+	}
+
+	cfg := &fetchConfig{
+		client:  &http.Client{},
+		headers: make(http.Header),
+	}
+
+	// WithCookieJar is applied before WithHTTPClient, a natural ordering
+	// when configuring both. The jar must still end up on the client that
+	// is actually used.
+	opts := []Option{
+		WithCookieJar(jar),
+		WithHTTPClient(&http.Client{}),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.cookieJar != nil {
+		cfg.client.Jar = cfg.cookieJar
+	}
+
+	if cfg.client.Jar != jar {
+		t.Fatalf("expected cookie jar to be set on the final client, got %v", cfg.client.Jar)
+	}
+}
@@ -0,0 +1,97 @@
+// Package readability extracts the readable content of an HTML page,
+// similar in spirit to Firefox's Reader View: it strips navigation,
+// ads, and boilerplate and returns the article's title, byline, and
+// cleaned-up content alongside its metadata.
+//
+// This is a small heuristic extractor — the candidate element is picked
+// by total paragraph text length (see scoreOf in content.go) — not a port
+// of github.com/go-shiori/go-readability or Mozilla's Readability.js. It
+// will misjudge the article body on pages those handle correctly (heavy
+// boilerplate, ad-laden layouts, multi-column sites). Treat it as a
+// stand-in to build against, not a drop-in replacement for either.
+package readability
+
+import (
+	"fmt"
+	"net/http"
+	nurl "net/url"
+	"time"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// Article is the result of extracting the readable content from a page.
+type Article struct {
+	Title       string
+	Byline      string
+	Node        *html.Node
+	Content     string
+	TextContent string
+	Length      int
+	Excerpt     string
+	SiteName    string
+	Image       string
+	Favicon     string
+	Language    string
+	// Images is populated by WithImageFetcher when image processing is
+	// requested through FromURLWithOptions.
+	Images []ImageAsset
+}
+
+// FromDocument extracts the readable content from an already-parsed
+// document. pageURL is used to resolve relative links and images in the
+// extracted content.
+func FromDocument(doc *html.Node, pageURL *nurl.URL) (Article, error) {
+	if doc == nil {
+		return Article{}, fmt.Errorf("document is nil")
+	}
+
+	clone := dom.Clone(doc, true)
+	meta := extractMetadata(clone, pageURL)
+
+	content, textContent, err := extractContent(clone, pageURL)
+	if err != nil {
+		return Article{}, fmt.Errorf("failed to extract content: %v", err)
+	}
+
+	return Article{
+		Title:       meta.title,
+		Byline:      meta.byline,
+		Node:        content,
+		Content:     dom.OuterHTML(content),
+		TextContent: textContent,
+		Length:      len(textContent),
+		Excerpt:     meta.excerpt,
+		SiteName:    meta.siteName,
+		Image:       meta.image,
+		Favicon:     meta.favicon,
+		Language:    meta.language,
+	}, nil
+}
+
+// FromURL fetches pageURL and extracts its readable content. For more
+// control over the request (custom headers, client, decompression, or
+// image handling) use FromURLWithOptions instead.
+func FromURL(pageURL string, timeout time.Duration) (Article, error) {
+	return FromURLWithOptions(pageURL, WithHTTPClient(&http.Client{Timeout: timeout}))
+}
+
+// CheckDocument reports whether doc looks like it contains a readable
+// article, without doing the (more expensive) full extraction.
+// FromDocument works on a clone and leaves doc intact, so CheckDocument
+// may be called before or after it.
+func CheckDocument(doc *html.Node) bool {
+	if doc == nil {
+		return false
+	}
+
+	const minContentLength = 140
+
+	var totalLength int
+	for _, p := range dom.QuerySelectorAll(doc, "p, pre, article") {
+		totalLength += len(dom.TextContent(p))
+	}
+
+	return totalLength >= minContentLength
+}
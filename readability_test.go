@@ -0,0 +1,29 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+// TestCheckDocumentAfterFromDocumentSeesOriginalContent guards against a
+// regression where FromDocument mutates doc in place: CheckDocument relies
+// on scoring the original <p>/<pre>/<article> elements, which extraction
+// strips away.
+func TestCheckDocumentAfterFromDocumentSeesOriginalContent(t *testing.T) {
+	html := `<html><body><article><p>` + strings.Repeat("word ", 40) + `</p></article></body></html>`
+
+	doc, err := dom.Parse(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+
+	if _, err := FromDocument(doc, nil); err != nil {
+		t.Fatalf("FromDocument returned error: %v", err)
+	}
+
+	if !CheckDocument(doc) {
+		t.Fatalf("expected CheckDocument to still see the original article content after FromDocument")
+	}
+}